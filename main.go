@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -15,6 +16,7 @@ import (
 	"github.com/autograde/aguis/scm"
 	"github.com/autograde/aguis/web"
 	"github.com/autograde/aguis/web/auth"
+	"github.com/autograde/aguis/web/hooks"
 	"github.com/gorilla/sessions"
 	_ "github.com/jinzhu/gorm/dialects/sqlite"
 	"github.com/labstack/echo"
@@ -34,6 +36,13 @@ func main() {
 		baseURL = flag.String("service.url", "localhost", "service base url")
 
 		fake = flag.Bool("provider.fake", false, "enable fake provider")
+
+		scmEnabled = flag.String("scm.enabled", "github,gitlab", "comma separated list of enabled SCM providers")
+
+		hooksSecret = flag.String("hooks.secret", envString("HOOKS_SECRET", ""), "secret used to verify incoming SCM webhooks")
+
+		jwtSecret  = flag.String("jwt.secret", envString("JWT_SECRET", ""), "HS256 secret used to sign API tokens; ignored if jwt.keyfile is set")
+		jwtKeyFile = flag.String("jwt.keyfile", "", "PEM-encoded RSA private key used to sign API tokens with RS256")
 	)
 	flag.Parse()
 
@@ -43,6 +52,10 @@ func main() {
 	logger := logrus.New()
 	e.Logger = web.EchoLogger{Logger: logger}
 
+	if err := scm.EnableOnly(strings.Split(*scmEnabled, ",")); err != nil {
+		logger.WithError(err).Fatal("invalid scm.enabled")
+	}
+
 	entryPoint := filepath.Join(*public, "index.html")
 	if !fileExists(entryPoint) {
 		logger.WithField("path", entryPoint).Warn("could not find file")
@@ -79,25 +92,41 @@ func main() {
 		logger.WithError(err).Fatal("could not connect to db")
 	}
 
-	e.GET("/logout", auth.OAuth2Logout())
+	jwtIssuer, err := auth.NewJWTIssuer(auth.TokenConfig{Secret: *jwtSecret, KeyFile: *jwtKeyFile}, auth.NewMemRevocationStore())
+	if err != nil {
+		logger.WithError(err).Fatal("could not set up JWT issuer")
+	}
+
+	// Source code management clients, cached by provider and access token.
+	scms := scm.NewClientPool(scm.PoolOptions{TTL: 30 * time.Minute, Capacity: 1000})
+
+	e.GET("/logout", auth.Logout(db, scms))
 
 	oauth2 := e.Group("/auth/:provider", withProvider, auth.PreAuth(db))
 	oauth2.GET("", auth.OAuth2Login(db))
-	oauth2.GET("/callback", auth.OAuth2Callback(db))
-
-	// Source code management clients indexed by access token.
-	scms := make(map[string]scm.SCM)
+	oauth2.GET("/callback", auth.CallbackAndWarm(db, scms))
 
 	api := e.Group("/api/v1")
-	api.Use(auth.AccessControl(db, scms))
+	api.Use(auth.JWTAuth(db, jwtIssuer), auth.AccessControl(db, scms))
 
 	api.GET("/user", web.GetSelf())
 	api.GET("/users/:id", web.GetUser(db))
 	api.GET("/users", web.GetUsers(db))
 
-	api.GET("/courses", web.ListCourses(db))
-	api.POST("/courses", web.NewCourse(logger, db))
-	api.POST("/directories", web.ListDirectories())
+	api.POST("/tokens", auth.NewToken(jwtIssuer))
+
+	hooksURL := "https://" + *baseURL + "/api/v1/hooks"
+
+	api.GET("/courses", web.ListCourses(db), auth.RequireScope(auth.ScopeCoursesRead))
+	api.POST("/courses", web.NewCourse(logger, db, scms, hooksURL, *hooksSecret))
+	api.POST("/courses/:id/bootstrap", web.BootstrapCourse(logger, db, scms, hooksURL, *hooksSecret))
+	api.POST("/directories", web.ListDirectories(logger, scms), auth.RequireScope(auth.ScopeDirectoriesWrite))
+
+	hooksQueue := hooks.NewQueue(hooksQueueSize, hooksWorkers, handleHookEvent(logger, db))
+	// Webhook deliveries carry no session or API token; they are
+	// authenticated by their provider signature instead, so the hook route
+	// is mounted without the api group's auth middleware.
+	hooks.RegisterRoutes(e.Group("/api/v1"), hooksQueue, *hooksSecret)
 
 	index := func(c echo.Context) error {
 		return c.File(entryPoint)
@@ -125,6 +154,32 @@ func main() {
 	if err := e.Shutdown(ctx); err != nil {
 		logger.WithError(err).Fatal("failure during server shutdown")
 	}
+	if err := hooksQueue.Shutdown(ctx); err != nil {
+		logger.WithError(err).Warn("hook queue did not drain before shutdown")
+	}
+}
+
+// hooksQueueSize and hooksWorkers bound the webhook dispatch worker pool
+// started in main.
+const (
+	hooksQueueSize = 256
+	hooksWorkers   = 4
+)
+
+// handleHookEvent returns the function run by the hook worker pool for each
+// dispatched hooks.Event.
+func handleHookEvent(logger *logrus.Logger, db database.Database) hooks.HandleFunc {
+	return func(ctx context.Context, event *hooks.Event) {
+		logger.WithFields(logrus.Fields{
+			"provider": event.Provider,
+			"repo":     event.Repo,
+			"ref":      event.Ref,
+			"commit":   event.Commit,
+			"pusher":   event.Pusher,
+		}).Info("received scm hook event")
+		// TODO: look up the course for event.Repo and hand the event off
+		// to the CI runner once one exists.
+	}
 }
 
 // In Windows, mime.type loads the file extensions from registry which
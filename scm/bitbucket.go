@@ -0,0 +1,162 @@
+package scm
+
+import (
+	"context"
+	"fmt"
+
+	bitbucket "github.com/gfleury/go-bitbucket-v1"
+)
+
+func init() {
+	Register("bitbucket", func(cfg Config) (SCM, error) {
+		if cfg.BaseURL == "" {
+			return nil, fmt.Errorf("bitbucket: BaseURL is required")
+		}
+		authCtx := context.WithValue(context.Background(), bitbucket.ContextAccessToken, cfg.Token)
+		client := bitbucket.NewAPIClient(authCtx, bitbucket.NewConfiguration(cfg.BaseURL))
+		return &BitbucketSCM{client: client}, nil
+	})
+}
+
+// BitbucketSCM implements the SCM interface for a self-hosted Bitbucket
+// Server instance.
+type BitbucketSCM struct {
+	client *bitbucket.APIClient
+}
+
+// NewBitbucketSCMClient returns a new Bitbucket Server client implementing
+// the SCM interface for the server at baseURL.
+func NewBitbucketSCMClient(baseURL, token string) (SCM, error) {
+	return NewSCMClientWithConfig("bitbucket", Config{BaseURL: baseURL, Token: token})
+}
+
+func (s *BitbucketSCM) ListDirectories(ctx context.Context) ([]*Directory, error) {
+	resp, err := s.client.DefaultApi.GetProjects(nil)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := bitbucket.GetProjectsResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	var directories []*Directory
+	for _, p := range projects {
+		directories = append(directories, &Directory{ID: uint64(p.ID), Name: p.Key})
+	}
+	return directories, nil
+}
+
+func (s *BitbucketSCM) CreateDirectory(ctx context.Context, opt *CreateDirectoryOptions) (*Directory, error) {
+	resp, err := s.client.DefaultApi.CreateProject(bitbucket.Project{
+		Key:  opt.Name,
+		Name: opt.Path,
+	})
+	if err != nil {
+		return nil, err
+	}
+	project, err := bitbucket.GetProjectResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &Directory{ID: uint64(project.ID), Name: project.Key}, nil
+}
+
+func (s *BitbucketSCM) GetDirectory(ctx context.Context, id uint64) (*Directory, error) {
+	directories, err := s.ListDirectories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range directories {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("bitbucket: no project with ID %d", id)
+}
+
+func (s *BitbucketSCM) CreateRepository(ctx context.Context, opt *CreateRepositoryOptions) (*Repository, error) {
+	resp, err := s.client.DefaultApi.CreateRepository(opt.Directory.Name, bitbucket.Repository{
+		Name:   opt.Path,
+		Public: !opt.Private,
+	})
+	if err != nil {
+		return nil, err
+	}
+	repo, err := bitbucket.GetRepositoryResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		ID:          uint64(repo.ID),
+		Path:        repo.Slug,
+		DirectoryID: opt.Directory.ID,
+		Private:     !repo.Public,
+	}, nil
+}
+
+func (s *BitbucketSCM) AddCollaborator(ctx context.Context, opt *AddCollaboratorOptions) error {
+	directory, err := s.GetDirectory(ctx, opt.Repository.DirectoryID)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.DefaultApi.AddUserPermission(directory.Name, opt.Repository.Path, opt.Username, toBitbucketPermission(opt.Access))
+	return err
+}
+
+func (s *BitbucketSCM) CreateHook(ctx context.Context, opt *CreateHookOptions) error {
+	directory, err := s.GetDirectory(ctx, opt.Repository.DirectoryID)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.DefaultApi.CreateWebhook(directory.Name, opt.Repository.Path, bitbucket.Webhook{
+		Name:   "quickfeed",
+		URL:    opt.URL,
+		Active: true,
+		Events: []string{"repo:refs_changed", "pr:opened"},
+	})
+	return err
+}
+
+func (s *BitbucketSCM) GetUserInfo(ctx context.Context) (*UserInfo, error) {
+	resp, err := s.client.DefaultApi.GetCurrentUser()
+	if err != nil {
+		return nil, err
+	}
+	user, err := bitbucket.GetUserResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &UserInfo{ID: uint64(user.ID), Login: user.Name, Name: user.DisplayName, Email: user.EmailAddress}, nil
+}
+
+// ListTeams is not supported by Bitbucket Server, which groups repository
+// access by project permissions rather than teams.
+func (s *BitbucketSCM) ListTeams(ctx context.Context) ([]*Team, error) {
+	return nil, ErrNotSupported{SCM: "bitbucket", Method: "ListTeams"}
+}
+
+// CreateTeam is not supported by Bitbucket Server; see ListTeams.
+func (s *BitbucketSCM) CreateTeam(ctx context.Context, opt *CreateTeamOptions) (*Team, error) {
+	return nil, ErrNotSupported{SCM: "bitbucket", Method: "CreateTeam"}
+}
+
+func (s *BitbucketSCM) BootstrapCourse(ctx context.Context, directory *Directory, opt BootstrapOptions) (*BootstrapResult, error) {
+	return Bootstrapper{}.Bootstrap(ctx, s, directory, opt)
+}
+
+// Capabilities implements every optional SCM operation except the
+// team-related ones; see ListTeams and CreateTeam.
+func (s *BitbucketSCM) Capabilities() CapSet {
+	return NewCapSet(CapCreateRepository, CapAddCollaborator, CapCreateHook)
+}
+
+func toBitbucketPermission(access AccessLevel) string {
+	switch access {
+	case AccessAdmin:
+		return "REPO_ADMIN"
+	case AccessWrite:
+		return "REPO_WRITE"
+	default:
+		return "REPO_READ"
+	}
+}
@@ -0,0 +1,133 @@
+package scm
+
+import (
+	"context"
+	"fmt"
+)
+
+// BootstrapOptions configures how a course directory is provisioned.
+type BootstrapOptions struct {
+	// WebhookURL and WebhookSecret configure the push/PR webhook installed
+	// on each created repository. No webhook is installed if WebhookURL is
+	// empty.
+	WebhookURL    string
+	WebhookSecret string
+}
+
+// BootstrapResult reports what BootstrapCourse provisioned, so that a failed
+// or retried bootstrap can tell what is already in place.
+type BootstrapResult struct {
+	Repositories []*Repository
+	Teams        []*Team
+}
+
+// courseRepository describes one of the conventional repositories created
+// for every course directory.
+type courseRepository struct {
+	path    string
+	private bool
+}
+
+// courseRepositories are the conventional repositories created for every
+// course directory.
+var courseRepositories = []courseRepository{
+	{path: "course-info", private: false},
+	{path: "assignments", private: false},
+	{path: "tests", private: true},
+	{path: "solutions", private: true},
+}
+
+// courseTeam describes one of the conventional teams created for every
+// course directory, along with the access level it is granted.
+type courseTeam struct {
+	name   string
+	access AccessLevel
+}
+
+// courseTeams are the conventional teams created for every course
+// directory.
+var courseTeams = []courseTeam{
+	{name: "students", access: AccessRead},
+	{name: "teachers", access: AccessWrite},
+}
+
+// repositories returns the subset of repos the team should be granted
+// access to: teachers get every repository, while students only get the
+// ones that aren't private (i.e. not the tests or solutions repos).
+func (t courseTeam) repositories(repos []*Repository) []*Repository {
+	if t.name == "teachers" {
+		return repos
+	}
+	var granted []*Repository
+	for i, repo := range repos {
+		if i < len(courseRepositories) && !courseRepositories[i].private {
+			granted = append(granted, repo)
+		}
+	}
+	return granted
+}
+
+// Bootstrapper provisions the conventional set of repositories and teams for
+// a course directory using only the common SCM interface, so a single
+// implementation covers every backend capable of CreateRepository. It is the
+// shared implementation behind SCM.BootstrapCourse.
+type Bootstrapper struct{}
+
+// Bootstrap creates the course-info, assignments, tests and solutions
+// repositories in directory, applying the conventional visibility for each,
+// installs a webhook on every repository if opt.WebhookURL is set, and
+// creates the students and teachers teams. Webhooks are installed only if
+// the backend has CapCreateHook, and teams are created only if it has
+// CapCreateTeam; on a backend lacking one of these (e.g. GitLab and
+// Bitbucket Server have no CapCreateTeam) that step is skipped without an
+// error and no teams are created. Existing repositories are not skipped:
+// every conventional repository is created anew. On error, result still holds
+// whatever was created before the failing step, so a caller retrying a
+// failed bootstrap can tell what is already in place.
+func (Bootstrapper) Bootstrap(ctx context.Context, s SCM, directory *Directory, opt BootstrapOptions) (*BootstrapResult, error) {
+	caps := s.Capabilities()
+	if !caps.Has(CapCreateRepository) {
+		return nil, ErrNotSupported{SCM: directory.Name, Method: "BootstrapCourse"}
+	}
+
+	result := &BootstrapResult{}
+
+	for _, r := range courseRepositories {
+		repo, err := s.CreateRepository(ctx, &CreateRepositoryOptions{
+			Directory: directory,
+			Path:      r.path,
+			Private:   r.private,
+		})
+		if err != nil {
+			return result, fmt.Errorf("could not create repository %s: %w", r.path, err)
+		}
+		result.Repositories = append(result.Repositories, repo)
+
+		if caps.Has(CapCreateHook) && opt.WebhookURL != "" {
+			if err := s.CreateHook(ctx, &CreateHookOptions{
+				Repository: repo,
+				URL:        opt.WebhookURL,
+				Secret:     opt.WebhookSecret,
+			}); err != nil {
+				return result, fmt.Errorf("could not create webhook for %s: %w", r.path, err)
+			}
+		}
+	}
+
+	if caps.Has(CapCreateTeam) {
+		for _, t := range courseTeams {
+			team, err := s.CreateTeam(ctx, &CreateTeamOptions{
+				Directory:    directory,
+				Name:         t.name,
+				Access:       t.access,
+				Repositories: t.repositories(result.Repositories),
+			})
+			if err != nil {
+				return result, fmt.Errorf("could not create team %s: %w", t.name, err)
+			}
+			result.Teams = append(result.Teams, team)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,34 @@
+package scm
+
+// Capability identifies an optional SCM operation that not every backend is
+// able to support.
+type Capability string
+
+// Capabilities checked by callers before dispatching an optional operation.
+const (
+	CapCreateRepository Capability = "create-repository"
+	CapAddCollaborator  Capability = "add-collaborator"
+	CapCreateHook       Capability = "create-hook"
+	CapListTeams        Capability = "list-teams"
+	CapCreateTeam       Capability = "create-team"
+)
+
+// CapSet is the set of capabilities a Remote backend supports. Callers should
+// check it before calling an optional operation rather than relying on
+// ErrNotSupported, which is only returned after the call has already been
+// dispatched.
+type CapSet map[Capability]bool
+
+// NewCapSet returns a CapSet containing the given capabilities.
+func NewCapSet(caps ...Capability) CapSet {
+	s := make(CapSet, len(caps))
+	for _, c := range caps {
+		s[c] = true
+	}
+	return s
+}
+
+// Has reports whether the set contains the given capability.
+func (s CapSet) Has(cap Capability) bool {
+	return s[cap]
+}
@@ -0,0 +1,202 @@
+package scm
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+func init() {
+	Register("gitea", func(cfg Config) (SCM, error) {
+		if cfg.BaseURL == "" {
+			return nil, fmt.Errorf("gitea: BaseURL is required")
+		}
+		client, err := gitea.NewClient(cfg.BaseURL, gitea.SetToken(cfg.Token))
+		if err != nil {
+			return nil, err
+		}
+		return &GiteaSCM{client: client}, nil
+	})
+}
+
+// GiteaSCM implements the SCM interface for a self-hosted Gitea instance.
+type GiteaSCM struct {
+	client *gitea.Client
+}
+
+// NewGiteaSCMClient returns a new Gitea client implementing the SCM
+// interface for the Gitea instance at baseURL.
+func NewGiteaSCMClient(baseURL, token string) (SCM, error) {
+	return NewSCMClientWithConfig("gitea", Config{BaseURL: baseURL, Token: token})
+}
+
+func (s *GiteaSCM) ListDirectories(ctx context.Context) ([]*Directory, error) {
+	orgs, _, err := s.client.ListMyOrgs(gitea.ListOrgsOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var directories []*Directory
+	for _, org := range orgs {
+		directories = append(directories, &Directory{
+			ID:     uint64(org.ID),
+			Name:   org.UserName,
+			Avatar: org.AvatarURL,
+		})
+	}
+	return directories, nil
+}
+
+func (s *GiteaSCM) CreateDirectory(ctx context.Context, opt *CreateDirectoryOptions) (*Directory, error) {
+	org, _, err := s.client.CreateOrg(gitea.CreateOrgOption{
+		Name:       opt.Name,
+		FullName:   opt.Path,
+		Visibility: gitea.VisibleTypePrivate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Directory{ID: uint64(org.ID), Name: org.UserName, Avatar: org.AvatarURL}, nil
+}
+
+func (s *GiteaSCM) GetDirectory(ctx context.Context, id uint64) (*Directory, error) {
+	orgs, err := s.ListDirectories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range orgs {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("gitea: no organization with ID %d", id)
+}
+
+func (s *GiteaSCM) CreateRepository(ctx context.Context, opt *CreateRepositoryOptions) (*Repository, error) {
+	repo, _, err := s.client.CreateOrgRepo(opt.Directory.Name, gitea.CreateRepoOption{
+		Name:    opt.Path,
+		Private: opt.Private,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		ID:          uint64(repo.ID),
+		Path:        repo.Name,
+		DirectoryID: opt.Directory.ID,
+		Private:     repo.Private,
+	}, nil
+}
+
+func (s *GiteaSCM) AddCollaborator(ctx context.Context, opt *AddCollaboratorOptions) error {
+	owner, repo, err := s.repoOwnerAndName(ctx, opt.Repository)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.AddCollaborator(owner, repo, opt.Username, gitea.AddCollaboratorOption{
+		Permission: toGiteaPermission(opt.Access),
+	})
+	return err
+}
+
+func (s *GiteaSCM) CreateHook(ctx context.Context, opt *CreateHookOptions) error {
+	owner, repo, err := s.repoOwnerAndName(ctx, opt.Repository)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.client.CreateRepoHook(owner, repo, gitea.CreateHookOption{
+		Type:   "gitea",
+		Active: true,
+		Config: map[string]string{
+			"url":          opt.URL,
+			"secret":       opt.Secret,
+			"content_type": "json",
+		},
+	})
+	return err
+}
+
+func (s *GiteaSCM) GetUserInfo(ctx context.Context) (*UserInfo, error) {
+	user, _, err := s.client.GetMyUserInfo()
+	if err != nil {
+		return nil, err
+	}
+	return &UserInfo{
+		ID:    uint64(user.ID),
+		Login: user.UserName,
+		Name:  user.FullName,
+		Email: user.Email,
+	}, nil
+}
+
+func (s *GiteaSCM) ListTeams(ctx context.Context) ([]*Team, error) {
+	teams, _, err := s.client.SearchTeams("", gitea.SearchTeamsOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var result []*Team
+	for _, t := range teams {
+		result = append(result, &Team{ID: uint64(t.ID), Name: t.Name, Access: toCommonAccess(t.Permission)})
+	}
+	return result, nil
+}
+
+func (s *GiteaSCM) CreateTeam(ctx context.Context, opt *CreateTeamOptions) (*Team, error) {
+	team, _, err := s.client.CreateTeam(opt.Directory.Name, gitea.CreateTeamOption{
+		Name:       opt.Name,
+		Permission: toGiteaPermission(opt.Access),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Gitea does not take a repository list when creating a team; each
+	// repository must be attached afterwards for the team to actually have
+	// any access.
+	for _, repo := range opt.Repositories {
+		if _, err := s.client.AddTeamRepository(team.ID, opt.Directory.Name, repo.Path); err != nil {
+			return nil, fmt.Errorf("could not add repository %s to team %s: %w", repo.Path, team.Name, err)
+		}
+	}
+	return &Team{ID: uint64(team.ID), Name: team.Name, Access: opt.Access}, nil
+}
+
+func (s *GiteaSCM) BootstrapCourse(ctx context.Context, directory *Directory, opt BootstrapOptions) (*BootstrapResult, error) {
+	return Bootstrapper{}.Bootstrap(ctx, s, directory, opt)
+}
+
+// Capabilities implements all optional SCM operations.
+func (s *GiteaSCM) Capabilities() CapSet {
+	return NewCapSet(CapCreateRepository, CapAddCollaborator, CapCreateHook, CapListTeams, CapCreateTeam)
+}
+
+// repoOwnerAndName resolves the directory and repository path of a
+// Repository into Gitea's owner/name addressing scheme.
+func (s *GiteaSCM) repoOwnerAndName(ctx context.Context, repo *Repository) (owner, name string, err error) {
+	directory, err := s.GetDirectory(ctx, repo.DirectoryID)
+	if err != nil {
+		return "", "", err
+	}
+	return directory.Name, repo.Path, nil
+}
+
+func toGiteaPermission(access AccessLevel) string {
+	switch access {
+	case AccessAdmin:
+		return "admin"
+	case AccessWrite:
+		return "write"
+	default:
+		return "read"
+	}
+}
+
+func toCommonAccess(permission string) AccessLevel {
+	switch permission {
+	case "admin", "owner":
+		return AccessAdmin
+	case "write":
+		return AccessWrite
+	default:
+		return AccessRead
+	}
+}
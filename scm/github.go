@@ -0,0 +1,195 @@
+package scm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/github"
+	"golang.org/x/oauth2"
+)
+
+func init() {
+	Register("github", func(cfg Config) (SCM, error) {
+		ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: cfg.Token})
+		client := github.NewClient(oauth2.NewClient(context.Background(), ts))
+		return &GithubSCM{client: client}, nil
+	})
+}
+
+// GithubSCM implements the SCM interface for github.com.
+type GithubSCM struct {
+	client *github.Client
+}
+
+// NewGithubSCMClient returns a new GitHub client implementing the SCM
+// interface.
+func NewGithubSCMClient(token string) (SCM, error) {
+	return NewSCMClientWithConfig("github", Config{Token: token})
+}
+
+func (s *GithubSCM) ListDirectories(ctx context.Context) ([]*Directory, error) {
+	orgs, _, err := s.client.Organizations.List(ctx, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	var directories []*Directory
+	for _, org := range orgs {
+		directories = append(directories, &Directory{
+			ID:     uint64(org.GetID()),
+			Name:   org.GetLogin(),
+			Avatar: org.GetAvatarURL(),
+		})
+	}
+	return directories, nil
+}
+
+// CreateDirectory is not supported by GitHub, which has no API for creating
+// an organization.
+func (s *GithubSCM) CreateDirectory(ctx context.Context, opt *CreateDirectoryOptions) (*Directory, error) {
+	return nil, ErrNotSupported{SCM: "github", Method: "CreateDirectory"}
+}
+
+func (s *GithubSCM) GetDirectory(ctx context.Context, id uint64) (*Directory, error) {
+	directories, err := s.ListDirectories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range directories {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("github: no organization with ID %d", id)
+}
+
+func (s *GithubSCM) CreateRepository(ctx context.Context, opt *CreateRepositoryOptions) (*Repository, error) {
+	repo, _, err := s.client.Repositories.Create(ctx, opt.Directory.Name, &github.Repository{
+		Name:    github.String(opt.Path),
+		Private: github.Bool(opt.Private),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		ID:          uint64(repo.GetID()),
+		Path:        repo.GetName(),
+		DirectoryID: opt.Directory.ID,
+		Private:     repo.GetPrivate(),
+	}, nil
+}
+
+func (s *GithubSCM) AddCollaborator(ctx context.Context, opt *AddCollaboratorOptions) error {
+	owner, repo, err := s.repoOwnerAndName(ctx, opt.Repository)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Repositories.AddCollaborator(ctx, owner, repo, opt.Username, &github.RepositoryAddCollaboratorOptions{
+		Permission: toGithubPermission(opt.Access),
+	})
+	return err
+}
+
+func (s *GithubSCM) CreateHook(ctx context.Context, opt *CreateHookOptions) error {
+	owner, repo, err := s.repoOwnerAndName(ctx, opt.Repository)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.client.Repositories.CreateHook(ctx, owner, repo, &github.Hook{
+		Active: github.Bool(true),
+		Events: []string{"push", "pull_request"},
+		Config: map[string]interface{}{
+			"url":          opt.URL,
+			"secret":       opt.Secret,
+			"content_type": "json",
+		},
+	})
+	return err
+}
+
+func (s *GithubSCM) GetUserInfo(ctx context.Context) (*UserInfo, error) {
+	user, _, err := s.client.Users.Get(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return &UserInfo{
+		ID:    uint64(user.GetID()),
+		Login: user.GetLogin(),
+		Name:  user.GetName(),
+		Email: user.GetEmail(),
+	}, nil
+}
+
+func (s *GithubSCM) ListTeams(ctx context.Context) ([]*Team, error) {
+	teams, _, err := s.client.Teams.ListUserTeams(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []*Team
+	for _, t := range teams {
+		result = append(result, &Team{ID: uint64(t.GetID()), Name: t.GetName(), Access: toCommonAccessGithub(t.GetPermission())})
+	}
+	return result, nil
+}
+
+func (s *GithubSCM) CreateTeam(ctx context.Context, opt *CreateTeamOptions) (*Team, error) {
+	team, _, err := s.client.Teams.CreateTeam(ctx, opt.Directory.Name, github.NewTeam{
+		Name:       opt.Name,
+		Permission: github.String(toGithubPermission(opt.Access)),
+		RepoNames:  repoNames(opt.Repositories),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Team{ID: uint64(team.GetID()), Name: team.GetName(), Access: opt.Access}, nil
+}
+
+func (s *GithubSCM) BootstrapCourse(ctx context.Context, directory *Directory, opt BootstrapOptions) (*BootstrapResult, error) {
+	return Bootstrapper{}.Bootstrap(ctx, s, directory, opt)
+}
+
+// Capabilities implements all optional SCM operations.
+func (s *GithubSCM) Capabilities() CapSet {
+	return NewCapSet(CapCreateRepository, CapAddCollaborator, CapCreateHook, CapListTeams, CapCreateTeam)
+}
+
+// repoOwnerAndName resolves the directory and repository path of a
+// Repository into GitHub's owner/name addressing scheme.
+func (s *GithubSCM) repoOwnerAndName(ctx context.Context, repo *Repository) (owner, name string, err error) {
+	directory, err := s.GetDirectory(ctx, repo.DirectoryID)
+	if err != nil {
+		return "", "", err
+	}
+	return directory.Name, repo.Path, nil
+}
+
+func toGithubPermission(access AccessLevel) string {
+	switch access {
+	case AccessAdmin:
+		return "admin"
+	case AccessWrite:
+		return "push"
+	default:
+		return "pull"
+	}
+}
+
+// repoNames extracts the repository names CreateTeam's RepoNames option
+// expects from repos.
+func repoNames(repos []*Repository) []string {
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		names[i] = repo.Path
+	}
+	return names
+}
+
+func toCommonAccessGithub(permission string) AccessLevel {
+	switch permission {
+	case "admin":
+		return AccessAdmin
+	case "push":
+		return AccessWrite
+	default:
+		return AccessRead
+	}
+}
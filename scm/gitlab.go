@@ -0,0 +1,157 @@
+package scm
+
+import (
+	"context"
+	"fmt"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func init() {
+	Register("gitlab", func(cfg Config) (SCM, error) {
+		client := gitlab.NewClient(nil, cfg.Token)
+		if cfg.BaseURL != "" {
+			if err := client.SetBaseURL(cfg.BaseURL); err != nil {
+				return nil, err
+			}
+		}
+		return &GitlabSCM{client: client}, nil
+	})
+}
+
+// GitlabSCM implements the SCM interface for gitlab.com or a self-hosted
+// GitLab instance.
+type GitlabSCM struct {
+	client *gitlab.Client
+}
+
+// NewGitlabSCMClient returns a new GitLab client implementing the SCM
+// interface. An empty baseURL selects gitlab.com; a self-hosted instance
+// requires baseURL.
+func NewGitlabSCMClient(baseURL, token string) (SCM, error) {
+	return NewSCMClientWithConfig("gitlab", Config{BaseURL: baseURL, Token: token})
+}
+
+func (s *GitlabSCM) ListDirectories(ctx context.Context) ([]*Directory, error) {
+	groups, _, err := s.client.Groups.ListGroups(&gitlab.ListGroupsOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var directories []*Directory
+	for _, g := range groups {
+		directories = append(directories, &Directory{
+			ID:     uint64(g.ID),
+			Name:   g.Path,
+			Avatar: g.AvatarURL,
+		})
+	}
+	return directories, nil
+}
+
+func (s *GitlabSCM) CreateDirectory(ctx context.Context, opt *CreateDirectoryOptions) (*Directory, error) {
+	group, _, err := s.client.Groups.CreateGroup(&gitlab.CreateGroupOptions{
+		Name: gitlab.String(opt.Name),
+		Path: gitlab.String(opt.Path),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Directory{ID: uint64(group.ID), Name: group.Path, Avatar: group.AvatarURL}, nil
+}
+
+func (s *GitlabSCM) GetDirectory(ctx context.Context, id uint64) (*Directory, error) {
+	group, _, err := s.client.Groups.GetGroup(int(id))
+	if err != nil {
+		return nil, fmt.Errorf("gitlab: no group with ID %d: %w", id, err)
+	}
+	return &Directory{ID: uint64(group.ID), Name: group.Path, Avatar: group.AvatarURL}, nil
+}
+
+func (s *GitlabSCM) CreateRepository(ctx context.Context, opt *CreateRepositoryOptions) (*Repository, error) {
+	namespaceID := int(opt.Directory.ID)
+	visibility := gitlab.PublicVisibility
+	if opt.Private {
+		visibility = gitlab.PrivateVisibility
+	}
+	project, _, err := s.client.Projects.CreateProject(&gitlab.CreateProjectOptions{
+		Name:        gitlab.String(opt.Path),
+		Path:        gitlab.String(opt.Path),
+		NamespaceID: gitlab.Int(namespaceID),
+		Visibility:  &visibility,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Repository{
+		ID:          uint64(project.ID),
+		Path:        project.Path,
+		DirectoryID: opt.Directory.ID,
+		Private:     opt.Private,
+	}, nil
+}
+
+func (s *GitlabSCM) AddCollaborator(ctx context.Context, opt *AddCollaboratorOptions) error {
+	users, err := s.client.Users.ListUsers(&gitlab.ListUsersOptions{Username: gitlab.String(opt.Username)})
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return fmt.Errorf("gitlab: no user with username %s", opt.Username)
+	}
+	access := toGitlabAccessLevel(opt.Access)
+	_, _, err = s.client.ProjectMembers.AddProjectMember(int(opt.Repository.ID), &gitlab.AddProjectMemberOptions{
+		UserID:      users[0].ID,
+		AccessLevel: &access,
+	})
+	return err
+}
+
+func (s *GitlabSCM) CreateHook(ctx context.Context, opt *CreateHookOptions) error {
+	_, _, err := s.client.Projects.AddProjectHook(int(opt.Repository.ID), &gitlab.AddProjectHookOptions{
+		URL:                 gitlab.String(opt.URL),
+		Token:               gitlab.String(opt.Secret),
+		PushEvents:          gitlab.Bool(true),
+		MergeRequestsEvents: gitlab.Bool(true),
+	})
+	return err
+}
+
+func (s *GitlabSCM) GetUserInfo(ctx context.Context) (*UserInfo, error) {
+	user, _, err := s.client.Users.CurrentUser()
+	if err != nil {
+		return nil, err
+	}
+	return &UserInfo{ID: uint64(user.ID), Login: user.Username, Name: user.Name, Email: user.Email}, nil
+}
+
+// ListTeams is not supported by GitLab, which organizes access through
+// groups rather than teams.
+func (s *GitlabSCM) ListTeams(ctx context.Context) ([]*Team, error) {
+	return nil, ErrNotSupported{SCM: "gitlab", Method: "ListTeams"}
+}
+
+// CreateTeam is not supported by GitLab; see ListTeams.
+func (s *GitlabSCM) CreateTeam(ctx context.Context, opt *CreateTeamOptions) (*Team, error) {
+	return nil, ErrNotSupported{SCM: "gitlab", Method: "CreateTeam"}
+}
+
+func (s *GitlabSCM) BootstrapCourse(ctx context.Context, directory *Directory, opt BootstrapOptions) (*BootstrapResult, error) {
+	return Bootstrapper{}.Bootstrap(ctx, s, directory, opt)
+}
+
+// Capabilities implements every optional SCM operation except the
+// team-related ones; see ListTeams and CreateTeam.
+func (s *GitlabSCM) Capabilities() CapSet {
+	return NewCapSet(CapCreateRepository, CapAddCollaborator, CapCreateHook)
+}
+
+func toGitlabAccessLevel(access AccessLevel) gitlab.AccessLevelValue {
+	switch access {
+	case AccessAdmin:
+		return gitlab.OwnerPermissions
+	case AccessWrite:
+		return gitlab.DeveloperPermissions
+	default:
+		return gitlab.ReporterPermissions
+	}
+}
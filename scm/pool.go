@@ -0,0 +1,141 @@
+package scm
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// PoolOptions configures a ClientPool.
+type PoolOptions struct {
+	// TTL is how long a client may be served from cache, counted from when
+	// it was built, before it is rebuilt via NewSCMClient. Cache hits do not
+	// extend it. Zero disables TTL-based eviction.
+	TTL time.Duration
+	// Capacity is the maximum number of cached clients. Once reached, the
+	// least recently used entry is evicted to make room. Zero disables
+	// capacity-based eviction.
+	Capacity int
+}
+
+// PoolMetrics reports a ClientPool's cumulative cache counters.
+type PoolMetrics struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+type poolKey struct {
+	provider string
+	token    string
+}
+
+type poolEntry struct {
+	key      poolKey
+	client   SCM
+	expireAt time.Time
+}
+
+// ClientPool caches SCM clients keyed by provider and access token. Entries
+// built longer than PoolOptions.TTL ago, or evicted to stay within
+// PoolOptions.Capacity, are rebuilt from scratch on their next Get. Unlike a
+// plain map, a ClientPool with a non-zero Capacity never grows unbounded, and
+// Invalidate drops the clients cached for a token, e.g. on logout. An expired
+// entry is only removed by a Get for its key, by capacity eviction or by
+// Invalidate.
+type ClientPool struct {
+	mu      sync.RWMutex
+	opt     PoolOptions
+	entries map[poolKey]*list.Element
+	lru     *list.List // most recently used entry at the front
+
+	hits, misses, evictions uint64
+}
+
+// NewClientPool returns a ClientPool configured by opt.
+func NewClientPool(opt PoolOptions) *ClientPool {
+	return &ClientPool{
+		opt:     opt,
+		entries: make(map[poolKey]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Get returns a cached SCM client for provider and token, building and
+// caching one via NewSCMClient if none is cached or the cached one has
+// expired.
+func (p *ClientPool) Get(provider, token string) (SCM, error) {
+	key := poolKey{provider: provider, token: token}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if el, ok := p.entries[key]; ok {
+		entry := el.Value.(*poolEntry)
+		if p.opt.TTL == 0 || time.Now().Before(entry.expireAt) {
+			p.lru.MoveToFront(el)
+			p.hits++
+			return entry.client, nil
+		}
+		p.removeElement(el)
+		p.evictions++
+	}
+
+	p.misses++
+	client, err := NewSCMClient(provider, token)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &poolEntry{key: key, client: client}
+	if p.opt.TTL > 0 {
+		entry.expireAt = time.Now().Add(p.opt.TTL)
+	}
+	p.entries[key] = p.lru.PushFront(entry)
+
+	p.evictOverCapacity()
+	return client, nil
+}
+
+// Invalidate removes every cached client issued for token, e.g. because the
+// user logged out or their OAuth token was rotated.
+func (p *ClientPool) Invalidate(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for key, el := range p.entries {
+		if key.token == token {
+			p.removeElement(el)
+		}
+	}
+}
+
+// Metrics returns the pool's current cumulative counters.
+func (p *ClientPool) Metrics() PoolMetrics {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return PoolMetrics{Hits: p.hits, Misses: p.misses, Evictions: p.evictions}
+}
+
+// evictOverCapacity removes the least recently used entries until the pool
+// is within capacity. Caller must hold p.mu.
+func (p *ClientPool) evictOverCapacity() {
+	if p.opt.Capacity <= 0 {
+		return
+	}
+	for len(p.entries) > p.opt.Capacity {
+		el := p.lru.Back()
+		if el == nil {
+			return
+		}
+		p.removeElement(el)
+		p.evictions++
+	}
+}
+
+// removeElement removes el from both the LRU list and the entries map.
+// Caller must hold p.mu.
+func (p *ClientPool) removeElement(el *list.Element) {
+	entry := el.Value.(*poolEntry)
+	p.lru.Remove(el)
+	delete(p.entries, entry.key)
+}
@@ -0,0 +1,98 @@
+package scm
+
+import "fmt"
+
+// Config carries the information a Factory needs to construct an SCM
+// client for a registered provider.
+type Config struct {
+	Token string
+	// BaseURL is required for self-hosted providers such as Gitea or
+	// Bitbucket Server. GitHub ignores it; GitLab uses it if set and
+	// gitlab.com otherwise.
+	BaseURL string
+}
+
+// Factory creates a new SCM client from the given Config. Backends register
+// a Factory under a provider name via Register, typically from their own
+// init function.
+type Factory func(cfg Config) (SCM, error)
+
+var remotes = make(map[string]Factory)
+
+// enabled restricts Providers and NewSCMClientWithConfig to the subset of
+// registered backends passed to EnableOnly. A nil enabled means no
+// restriction has been configured, i.e. every registered backend is usable;
+// this is the default so that callers (and tests) that never call
+// EnableOnly keep working unchanged.
+var enabled map[string]bool
+
+// Register makes an SCM backend available under the given provider name.
+// It is meant to be called from the init function of the package
+// implementing the backend, and panics if called twice for the same name.
+func Register(name string, factory Factory) {
+	if factory == nil {
+		panic("scm: Register factory is nil for provider " + name)
+	}
+	if _, dup := remotes[name]; dup {
+		panic("scm: Register called twice for provider " + name)
+	}
+	remotes[name] = factory
+}
+
+// EnableOnly restricts Providers and NewSCMClientWithConfig to names,
+// e.g. to honor a --scm.enabled flag. It returns an error naming the first
+// entry in names that has no backend registered. Meant to be called once,
+// early in main, before the server starts serving requests.
+func EnableOnly(names []string) error {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		if _, ok := remotes[name]; !ok {
+			return fmt.Errorf("scm: cannot enable unregistered provider %s", name)
+		}
+		set[name] = true
+	}
+	enabled = set
+	return nil
+}
+
+// IsRegistered reports whether provider is both registered and, if
+// EnableOnly has been called, enabled, i.e. whether NewSCMClient would
+// recognize it.
+func IsRegistered(provider string) bool {
+	for _, registered := range Providers() {
+		if provider == registered {
+			return true
+		}
+	}
+	return false
+}
+
+// Providers returns the names of the SCM backends available for use: every
+// registered backend, or, once EnableOnly has been called, only the
+// backends it was given.
+func Providers() []string {
+	names := make([]string, 0, len(remotes))
+	for name := range remotes {
+		if enabled != nil && !enabled[name] {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// NewSCMClient returns a new provider client implementing the SCM interface.
+func NewSCMClient(provider, token string) (SCM, error) {
+	return NewSCMClientWithConfig(provider, Config{Token: token})
+}
+
+// NewSCMClientWithConfig returns a new provider client implementing the SCM
+// interface, using cfg to configure it. Self-hosted providers such as Gitea
+// or Bitbucket Server require cfg.BaseURL to be set.
+func NewSCMClientWithConfig(provider string, cfg Config) (SCM, error) {
+	factory, ok := remotes[provider]
+	if !ok || !IsRegistered(provider) {
+		return nil, fmt.Errorf("invalid provider: %s", provider)
+	}
+	return factory(cfg)
+}
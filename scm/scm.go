@@ -2,11 +2,10 @@ package scm
 
 import (
 	"context"
-	"errors"
 )
 
 // SCM is a common interface for different source code management solutions,
-// i.e., GitHub and GitLab.
+// i.e., GitHub, GitLab, Gitea and Bitbucket Server.
 type SCM interface {
 	// Lists directories which can be used as a course directory.
 	ListDirectories(context.Context) ([]*Directory, error)
@@ -14,17 +13,31 @@ type SCM interface {
 	CreateDirectory(context.Context, *CreateDirectoryOptions) (*Directory, error)
 	// Gets a directory.
 	GetDirectory(context.Context, uint64) (*Directory, error)
-}
 
-// NewSCMClient returns a new provider client implementing the SCM interface.
-func NewSCMClient(provider, token string) (SCM, error) {
-	switch provider {
-	case "github":
-		return NewGithubSCMClient(token), nil
-	case "gitlab":
-		return NewGitlabSCMClient(token), nil
-	}
-	return nil, errors.New("invalid provider: " + provider)
+	// Creates a new repository in the given directory.
+	CreateRepository(context.Context, *CreateRepositoryOptions) (*Repository, error)
+	// Adds a collaborator to a repository with the given permission level.
+	AddCollaborator(context.Context, *AddCollaboratorOptions) error
+	// Creates a webhook for a repository.
+	CreateHook(context.Context, *CreateHookOptions) error
+	// Gets information about the user associated with the client's token.
+	GetUserInfo(context.Context) (*UserInfo, error)
+	// Lists the teams the user has access to.
+	ListTeams(context.Context) ([]*Team, error)
+	// Creates a team with the given access level in a directory.
+	CreateTeam(context.Context, *CreateTeamOptions) (*Team, error)
+
+	// BootstrapCourse provisions the conventional set of repositories and
+	// teams for a newly created course directory.
+	BootstrapCourse(context.Context, *Directory, BootstrapOptions) (*BootstrapResult, error)
+
+	// Capabilities reports which of the optional operations named by a
+	// Capability constant this backend actually implements, so that callers
+	// can degrade gracefully instead of dispatching a call that is only
+	// rejected with ErrNotSupported after the fact. CreateDirectory has no
+	// Capability; a backend that cannot create directories returns
+	// ErrNotSupported from it.
+	Capabilities() CapSet
 }
 
 // Directory represents an entity which is capable of managing source code
@@ -42,6 +55,78 @@ type CreateDirectoryOptions struct {
 	Name string
 }
 
+// Repository represents a source code repository hosted by an SCM.
+type Repository struct {
+	ID          uint64 `json:"id"`
+	Path        string `json:"path"`
+	DirectoryID uint64 `json:"directoryid"`
+	Private     bool   `json:"private"`
+}
+
+// CreateRepositoryOptions contains information on how a repository should be
+// created.
+type CreateRepositoryOptions struct {
+	Directory *Directory
+	Path      string
+	Private   bool
+}
+
+// AccessLevel is the permission granted to a collaborator or team on a
+// repository.
+type AccessLevel string
+
+// Access levels supported across backends. Not every backend distinguishes
+// between all of these; implementations should map to the closest available
+// level.
+const (
+	AccessRead  AccessLevel = "read"
+	AccessWrite AccessLevel = "write"
+	AccessAdmin AccessLevel = "admin"
+)
+
+// AddCollaboratorOptions contains information on how a collaborator should be
+// added to a repository.
+type AddCollaboratorOptions struct {
+	Repository *Repository
+	Username   string
+	Access     AccessLevel
+}
+
+// CreateHookOptions contains information on how a webhook should be created.
+type CreateHookOptions struct {
+	Repository *Repository
+	URL        string
+	Secret     string
+}
+
+// UserInfo holds the information an SCM exposes about the user associated
+// with a client's access token.
+type UserInfo struct {
+	ID    uint64 `json:"id"`
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+// Team represents a group of users with shared access to one or more
+// repositories.
+type Team struct {
+	ID     uint64 `json:"id"`
+	Name   string `json:"name"`
+	Access AccessLevel
+}
+
+// CreateTeamOptions contains information on how a team should be created.
+type CreateTeamOptions struct {
+	Directory *Directory
+	Name      string
+	Access    AccessLevel
+	// Repositories are granted Access to the team, in addition to
+	// whatever repositories the backend's CreateTeam call itself may add
+	// the team to.
+	Repositories []*Repository
+}
+
 // ErrNotSupported is returned when the source code management solution used
 // does not provide a sufficient API for the method called.
 type ErrNotSupported struct {
@@ -50,5 +135,5 @@ type ErrNotSupported struct {
 }
 
 func (e ErrNotSupported) Error() string {
-	return "method" + e.Method + " not supported by " + e.SCM + " SCM"
+	return "method " + e.Method + " not supported by " + e.SCM + " SCM"
 }
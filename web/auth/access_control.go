@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/autograde/aguis/database"
+	"github.com/autograde/aguis/models"
+	"github.com/autograde/aguis/scm"
+	"github.com/labstack/echo"
+	"github.com/labstack/echo-contrib/session"
+)
+
+// sessionUserKey is the gorilla session key OAuth2Callback stores the
+// logged-in user's ID under.
+const sessionUserKey = "user_id"
+
+// AccessControl returns a middleware that resolves the logged-in user —
+// from the context if JWTAuth already set one, otherwise from the session
+// cookie — and, for each of the user's remote identities, makes a pooled SCM
+// client available in the request context under the identity's provider
+// name (e.g. c.Get("github")).
+func AccessControl(db database.Database, pool *scm.ClientPool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user, _ := c.Get("user").(*models.User)
+			if user == nil {
+				sess, err := session.Get("session", c)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusUnauthorized, "not logged in")
+				}
+				id, ok := sess.Values[sessionUserKey].(uint64)
+				if !ok {
+					return echo.NewHTTPError(http.StatusUnauthorized, "not logged in")
+				}
+				user, err = db.GetUser(id)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusUnauthorized, "not logged in")
+				}
+				c.Set("user", user)
+			}
+
+			for _, remote := range user.RemoteIdentities {
+				client, err := pool.Get(remote.Provider, remote.AccessToken)
+				if err != nil {
+					continue
+				}
+				c.Set(remote.Provider, client)
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+func TestJWTIssuerRoundTrip(t *testing.T) {
+	issuer, err := NewJWTIssuer(TokenConfig{Secret: "test-secret"}, NewMemRevocationStore())
+	if err != nil {
+		t.Fatalf("NewJWTIssuer: %v", err)
+	}
+
+	token, err := issuer.Issue(42, []Scope{ScopeCoursesRead})
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	claims, err := issuer.Parse(token)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	userID, err := claims.UserID()
+	if err != nil {
+		t.Fatalf("UserID: %v", err)
+	}
+	if userID != 42 {
+		t.Errorf("UserID() = %d, want 42", userID)
+	}
+	if !claims.HasScope(ScopeCoursesRead) {
+		t.Errorf("claims missing ScopeCoursesRead")
+	}
+	if claims.HasScope(ScopeDirectoriesWrite) {
+		t.Errorf("claims should not have ScopeDirectoriesWrite")
+	}
+}
+
+func TestJWTIssuerRevocation(t *testing.T) {
+	issuer, err := NewJWTIssuer(TokenConfig{Secret: "test-secret"}, NewMemRevocationStore())
+	if err != nil {
+		t.Fatalf("NewJWTIssuer: %v", err)
+	}
+
+	token, err := issuer.Issue(1, nil)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	claims, err := issuer.Parse(token)
+	if err != nil {
+		t.Fatalf("Parse before revoke: %v", err)
+	}
+	if err := issuer.Revoke(claims); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := issuer.Parse(token); err == nil {
+		t.Errorf("Parse after revoke: expected error, got nil")
+	}
+}
+
+func TestJWTIssuerExpiredToken(t *testing.T) {
+	issuer, err := NewJWTIssuer(TokenConfig{Secret: "test-secret", TTL: -time.Minute}, NewMemRevocationStore())
+	if err != nil {
+		t.Fatalf("NewJWTIssuer: %v", err)
+	}
+
+	token, err := issuer.Issue(1, nil)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if _, err := issuer.Parse(token); err == nil {
+		t.Errorf("Parse of expired token: expected error, got nil")
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []Scope
+		tagged  bool
+		wantErr bool
+	}{
+		{name: "session-authenticated request is unrestricted", tagged: false, wantErr: false},
+		{name: "token with required scope passes", scopes: []Scope{ScopeCoursesRead}, tagged: true, wantErr: false},
+		{name: "token missing required scope is rejected", scopes: []Scope{ScopeDirectoriesWrite}, tagged: true, wantErr: true},
+		{name: "token with no scopes is rejected", scopes: nil, tagged: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			if tt.tagged {
+				c.Set(scopesContextKey, tt.scopes)
+			}
+
+			called := false
+			next := func(c echo.Context) error {
+				called = true
+				return nil
+			}
+
+			err := RequireScope(ScopeCoursesRead)(next)(c)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				if called {
+					t.Errorf("next was called despite missing scope")
+				}
+			} else {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				if !called {
+					t.Errorf("next was not called")
+				}
+			}
+		})
+	}
+}
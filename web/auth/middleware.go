@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/autograde/aguis/database"
+	"github.com/labstack/echo"
+	"github.com/labstack/echo-contrib/session"
+)
+
+// scopesContextKey is the echo.Context key holding the scopes granted to a
+// JWT-authenticated request. Requests authenticated via the session cookie
+// carry no scopes key, i.e. they are unrestricted.
+const scopesContextKey = "scopes"
+
+// JWTAuth returns a middleware that authenticates a request from its
+// Authorization: Bearer header when neither a user is already set nor the
+// session cookie identifies a logged-in user, populating c.Set("user", ...) the same way the session-based
+// AccessControl middleware does. It must run before AccessControl so that
+// AccessControl sees the user JWTAuth resolved.
+func JWTAuth(db database.Database, issuer *JWTIssuer) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Get("user") != nil {
+				return next(c)
+			}
+			if sess, err := session.Get("session", c); err == nil {
+				if _, ok := sess.Values[sessionUserKey].(uint64); ok {
+					return next(c)
+				}
+			}
+
+			header := c.Request().Header.Get("Authorization")
+			const prefix = "Bearer "
+			if !strings.HasPrefix(header, prefix) {
+				return next(c)
+			}
+
+			claims, err := issuer.Parse(strings.TrimPrefix(header, prefix))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid token")
+			}
+
+			userID, err := claims.UserID()
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid token subject")
+			}
+
+			user, err := db.GetUser(userID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid token subject")
+			}
+
+			c.Set("user", user)
+			c.Set(scopesContextKey, claims.Scopes)
+			return next(c)
+		}
+	}
+}
+
+// RequireScope returns a middleware that rejects requests which were
+// authenticated via a JWT lacking scope. Session-authenticated requests,
+// which carry no scopes in the context, are let through unrestricted.
+func RequireScope(scope Scope) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			scopes, ok := c.Get(scopesContextKey).([]Scope)
+			if !ok {
+				return next(c)
+			}
+			for _, s := range scopes {
+				if s == scope {
+					return next(c)
+				}
+			}
+			return echo.NewHTTPError(http.StatusForbidden, "token missing required scope: "+string(scope))
+		}
+	}
+}
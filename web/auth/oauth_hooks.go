@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"github.com/autograde/aguis/database"
+	"github.com/autograde/aguis/models"
+	"github.com/autograde/aguis/scm"
+	"github.com/labstack/echo"
+	"github.com/labstack/echo-contrib/session"
+)
+
+// Logout invalidates the caller's cached SCM clients in pool before
+// deferring to OAuth2Logout to clear the session, so a logged-out user's
+// tokens stop serving cached clients instead of lingering until their TTL.
+func Logout(db database.Database, pool *scm.ClientPool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		invalidateSCMClients(db, pool, c)
+		return OAuth2Logout()(c)
+	}
+}
+
+// CallbackAndWarm wraps OAuth2Callback, additionally warming pool's cache
+// for the user's remote identities so the first API request after login
+// doesn't pay the cost of building an SCM client.
+func CallbackAndWarm(db database.Database, pool *scm.ClientPool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := OAuth2Callback(db)(c); err != nil {
+			return err
+		}
+		warmSCMClients(db, pool, c)
+		return nil
+	}
+}
+
+// sessionUser looks up the user the session cookie identifies, if any.
+func sessionUser(db database.Database, c echo.Context) *models.User {
+	sess, err := session.Get("session", c)
+	if err != nil {
+		return nil
+	}
+	id, ok := sess.Values[sessionUserKey].(uint64)
+	if !ok {
+		return nil
+	}
+	user, err := db.GetUser(id)
+	if err != nil {
+		return nil
+	}
+	return user
+}
+
+func invalidateSCMClients(db database.Database, pool *scm.ClientPool, c echo.Context) {
+	user := sessionUser(db, c)
+	if user == nil {
+		return
+	}
+	for _, remote := range user.RemoteIdentities {
+		pool.Invalidate(remote.AccessToken)
+	}
+}
+
+func warmSCMClients(db database.Database, pool *scm.ClientPool, c echo.Context) {
+	user := sessionUser(db, c)
+	if user == nil {
+		return
+	}
+	for _, remote := range user.RemoteIdentities {
+		pool.Get(remote.Provider, remote.AccessToken)
+	}
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// RevocationStore records revoked token IDs (JWT "jti" claims) so that
+// JWTIssuer.Parse can reject them even while otherwise valid.
+type RevocationStore interface {
+	// Revoke marks id as revoked until expiry, after which it may be
+	// forgotten since the token would no longer validate anyway.
+	Revoke(id string, expiry time.Time) error
+	// IsRevoked reports whether id has been revoked.
+	IsRevoked(id string) (bool, error)
+}
+
+// memRevocationStore is an in-memory RevocationStore. Revocations are not
+// persisted to the database and are lost when the process restarts.
+type memRevocationStore struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+// NewMemRevocationStore returns a RevocationStore that keeps revoked token
+// IDs in memory for the lifetime of the process.
+func NewMemRevocationStore() RevocationStore {
+	return &memRevocationStore{revoked: make(map[string]time.Time)}
+}
+
+func (s *memRevocationStore) Revoke(id string, expiry time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked[id] = expiry
+	return nil
+}
+
+func (s *memRevocationStore) IsRevoked(id string) (bool, error) {
+	s.mu.RLock()
+	expiry, ok := s.revoked[id]
+	s.mu.RUnlock()
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(expiry) {
+		s.mu.Lock()
+		delete(s.revoked, id)
+		s.mu.Unlock()
+		return false, nil
+	}
+	return true, nil
+}
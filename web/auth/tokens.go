@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/autograde/aguis/models"
+	"github.com/labstack/echo"
+)
+
+// NewTokenRequest represents a request to mint an API token for the
+// logged-in user.
+type NewTokenRequest struct {
+	Scopes []Scope `json:"scopes"`
+}
+
+// NewTokenResponse carries a freshly minted API token.
+type NewTokenResponse struct {
+	Token string `json:"token"`
+}
+
+// mintableScopes are the scopes NewToken will mint a token for. A request
+// naming any other scope is rejected.
+var mintableScopes = map[Scope]bool{
+	ScopeCoursesRead:      true,
+	ScopeDirectoriesWrite: true,
+}
+
+// NewToken mints a scoped JWT for the currently logged-in user, for use by
+// clients that cannot carry the session cookie, e.g. CLI tools and CI
+// runners. It may only be called with the session cookie, not an existing
+// API token, so that a token can't be used to mint another, broader one.
+func NewToken(issuer *JWTIssuer) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if _, tokenAuthenticated := c.Get(scopesContextKey).([]Scope); tokenAuthenticated {
+			return echo.NewHTTPError(http.StatusForbidden, "cannot mint a token from an existing token")
+		}
+
+		user := c.Get("user").(*models.User)
+
+		var tr NewTokenRequest
+		if err := c.Bind(&tr); err != nil {
+			return err
+		}
+		for _, s := range tr.Scopes {
+			if !mintableScopes[s] {
+				return echo.NewHTTPError(http.StatusBadRequest, "unknown scope: "+string(s))
+			}
+		}
+
+		token, err := issuer.Issue(user.ID, tr.Scopes)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusCreated, &NewTokenResponse{Token: token})
+	}
+}
@@ -2,20 +2,28 @@ package web
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/autograde/aguis/database"
 	"github.com/autograde/aguis/models"
 	"github.com/autograde/aguis/scm"
 	"github.com/labstack/echo"
 )
 
-// MaxWait is the maximum time a request is allowed to stay open before
-// aborting.
+// MaxWait is the maximum time a single SCM API call, such as a directory
+// lookup, is allowed to take.
 const MaxWait = 10 * time.Second
 
+// BootstrapMaxWait is the maximum time a full course bootstrap is allowed to
+// take. Bootstrapping makes up to 4 CreateRepository, 4 CreateHook and 2
+// CreateTeam calls in sequence, so it needs a much larger budget than a
+// single SCM API call gets under MaxWait.
+const BootstrapMaxWait = 60 * time.Second
+
 // NewCourseRequest represents a request for a new course.
 type NewCourseRequest struct {
 	Name string `json:"name"`
@@ -31,7 +39,7 @@ func (cr *NewCourseRequest) valid() bool {
 	return cr != nil &&
 		cr.Name != "" &&
 		cr.Code != "" &&
-		(cr.Provider == "github" || cr.Provider == "gitlab") &&
+		scm.IsRegistered(cr.Provider) &&
 		cr.DirectoryID != 0 &&
 		cr.Year != 0 &&
 		cr.Tag != ""
@@ -60,8 +68,11 @@ func ListCourses(db database.Database) echo.HandlerFunc {
 	}
 }
 
-// NewCourse creates a new course and associates it with an organization.
-func NewCourse(db database.Database) echo.HandlerFunc {
+// NewCourse creates a new course, associates it with an organization, and
+// bootstraps the course directory's conventional repositories and teams.
+// hooksURL is the base URL new repositories' webhooks are pointed at, e.g.
+// https://example.com/api/v1/hooks; the provider name is appended to it.
+func NewCourse(logger *logrus.Logger, db database.Database, pool *scm.ClientPool, hooksURL, hooksSecret string) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		var cr NewCourseRequest
 		if err := c.Bind(&cr); err != nil {
@@ -71,10 +82,12 @@ func NewCourse(db database.Database) echo.HandlerFunc {
 			return echo.NewHTTPError(http.StatusBadRequest, "invalid payload")
 		}
 
-		if c.Get(cr.Provider) == nil {
-			return echo.NewHTTPError(http.StatusBadRequest, "provider "+cr.Provider+" not registered")
+		user := c.Get("user").(*models.User)
+		s, err := scmClientFor(pool, user, cr.Provider)
+		if err != nil {
+			logger.WithError(err).WithField("provider", cr.Provider).Warn("could not get SCM client")
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 		}
-		s := c.Get(cr.Provider).(scm.SCM)
 
 		ctx, cancel := context.WithTimeout(c.Request().Context(), MaxWait)
 		defer cancel()
@@ -86,7 +99,6 @@ func NewCourse(db database.Database) echo.HandlerFunc {
 		}
 
 		// TODO: Does the user have sufficient rights?
-		// TODO: Initialize directory?
 
 		course := models.Course{
 			Name:        cr.Name,
@@ -101,6 +113,113 @@ func NewCourse(db database.Database) echo.HandlerFunc {
 			return err
 		}
 
+		bootstrapCtx, bootstrapCancel := context.WithTimeout(c.Request().Context(), BootstrapMaxWait)
+		defer bootstrapCancel()
+
+		if _, err := s.BootstrapCourse(bootstrapCtx, directory, bootstrapOptions(hooksURL, hooksSecret, cr.Provider)); err != nil {
+			logger.WithError(err).WithField("course", cr.Code).Error("could not bootstrap course directory")
+			// The course row is kept, so POST /courses/:id/bootstrap can
+			// retry for this course ID. The row is not marked as failed, and
+			// the repositories and teams BootstrapCourse created before
+			// failing are not recorded; the retry creates every conventional
+			// repository again.
+			return echo.NewHTTPError(http.StatusBadGateway, "could not initialize course directory; retry with POST /courses/"+strconv.FormatUint(course.ID, 10)+"/bootstrap")
+		}
+
 		return c.JSONPretty(http.StatusCreated, &course, "\t")
 	}
 }
+
+// BootstrapCourse (re-)provisions the repositories and teams for an
+// already-created course, for use when NewCourse's initial bootstrap failed.
+func BootstrapCourse(logger *logrus.Logger, db database.Database, pool *scm.ClientPool, hooksURL, hooksSecret string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil || id == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid course id")
+		}
+
+		course, err := db.GetCourse(id)
+		if err != nil {
+			return err
+		}
+
+		user := c.Get("user").(*models.User)
+		if err := authorizeCourseAccess(db, user, course.ID); err != nil {
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		}
+
+		s, err := scmClientFor(pool, user, course.Provider)
+		if err != nil {
+			logger.WithError(err).WithField("provider", course.Provider).Warn("could not get SCM client")
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request().Context(), MaxWait)
+		defer cancel()
+
+		directory, err := s.GetDirectory(ctx, course.DirectoryID)
+		if err != nil {
+			return err
+		}
+
+		bootstrapCtx, bootstrapCancel := context.WithTimeout(c.Request().Context(), BootstrapMaxWait)
+		defer bootstrapCancel()
+
+		result, err := s.BootstrapCourse(bootstrapCtx, directory, bootstrapOptions(hooksURL, hooksSecret, course.Provider))
+		if err != nil {
+			logger.WithError(err).WithField("course", course.Code).Error("could not bootstrap course directory")
+			return echo.NewHTTPError(http.StatusBadGateway, "could not initialize course directory")
+		}
+
+		return c.JSONPretty(http.StatusOK, result, "\t")
+	}
+}
+
+// bootstrapOptions builds the scm.BootstrapOptions used to provision a
+// course directory, pointing the webhook at the given provider's endpoint.
+func bootstrapOptions(hooksURL, hooksSecret, provider string) scm.BootstrapOptions {
+	return scm.BootstrapOptions{
+		WebhookURL:    hooksURL + "/" + provider,
+		WebhookSecret: hooksSecret,
+	}
+}
+
+// scmClientFor returns a pooled SCM client for provider, using the access
+// token from user's remote identity for that provider.
+func scmClientFor(pool *scm.ClientPool, user *models.User, provider string) (scm.SCM, error) {
+	accessToken, ok := remoteToken(user, provider)
+	if !ok {
+		return nil, fmt.Errorf("no remote identity for provider %s", provider)
+	}
+	return pool.Get(provider, accessToken)
+}
+
+// remoteToken returns the access token from user's remote identity for
+// provider, if any.
+func remoteToken(user *models.User, provider string) (string, bool) {
+	for _, remote := range user.RemoteIdentities {
+		if remote.Provider == provider {
+			return remote.AccessToken, true
+		}
+	}
+	return "", false
+}
+
+// authorizeCourseAccess returns an error unless user is associated with
+// courseID, so that POST /courses/:id/bootstrap cannot be used to retrigger
+// bootstrap for an arbitrary course.
+// TODO: restrict this to the teacher role once database.Database exposes
+// that distinction; for now any enrollment is accepted.
+func authorizeCourseAccess(db database.Database, user *models.User, courseID uint64) error {
+	courses, err := db.GetCoursesForUser(user.ID)
+	if err != nil {
+		return err
+	}
+	for _, course := range *courses {
+		if course.ID == courseID {
+			return nil
+		}
+	}
+	return fmt.Errorf("user %d is not associated with course %d", user.ID, courseID)
+}
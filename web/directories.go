@@ -0,0 +1,50 @@
+package web
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/autograde/aguis/models"
+	"github.com/autograde/aguis/scm"
+	"github.com/labstack/echo"
+)
+
+// DirectoryListing reports the directories available through one of the
+// user's remote identities.
+type DirectoryListing struct {
+	Provider    string           `json:"provider"`
+	Directories []*scm.Directory `json:"directories"`
+}
+
+// ListDirectories lists the directories available to the logged-in user
+// across every provider they have a remote identity for, skipping providers
+// whose client can't be built or that don't support listing directories
+// rather than failing the whole request.
+func ListDirectories(logger *logrus.Logger, pool *scm.ClientPool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*models.User)
+
+		ctx, cancel := context.WithTimeout(c.Request().Context(), MaxWait)
+		defer cancel()
+
+		var listings []*DirectoryListing
+		for _, remote := range user.RemoteIdentities {
+			s, err := pool.Get(remote.Provider, remote.AccessToken)
+			if err != nil {
+				logger.WithError(err).WithField("provider", remote.Provider).Warn("could not get SCM client")
+				continue
+			}
+
+			directories, err := s.ListDirectories(ctx)
+			if err != nil {
+				logger.WithError(err).WithField("provider", remote.Provider).Warn("could not list directories")
+				continue
+			}
+
+			listings = append(listings, &DirectoryListing{Provider: remote.Provider, Directories: directories})
+		}
+
+		return c.JSONPretty(http.StatusOK, listings, "\t")
+	}
+}
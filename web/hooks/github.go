@@ -0,0 +1,77 @@
+package hooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+func init() {
+	Register("github", githubParser{})
+}
+
+// githubParser parses GitHub webhook deliveries, signed with
+// X-Hub-Signature-256.
+type githubParser struct{}
+
+func (githubParser) Verify(r *http.Request, body []byte, secret string) error {
+	sig := r.Header.Get("X-Hub-Signature-256")
+	if sig == "" {
+		return errors.New("missing X-Hub-Signature-256 header")
+	}
+	return verifyHMACSHA256("sha256=", sig, body, secret)
+}
+
+func (githubParser) Parse(r *http.Request, body []byte) (*Event, error) {
+	switch r.Header.Get("X-GitHub-Event") {
+	case "push":
+		var payload struct {
+			Ref        string `json:"ref"`
+			After      string `json:"after"`
+			Repository struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+			Pusher struct {
+				Name string `json:"name"`
+			} `json:"pusher"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, err
+		}
+		return &Event{
+			Repo:   payload.Repository.FullName,
+			Ref:    payload.Ref,
+			Commit: payload.After,
+			Pusher: payload.Pusher.Name,
+		}, nil
+
+	case "pull_request":
+		var payload struct {
+			PullRequest struct {
+				Head struct {
+					Ref string `json:"ref"`
+					SHA string `json:"sha"`
+				} `json:"head"`
+			} `json:"pull_request"`
+			Repository struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+			Sender struct {
+				Login string `json:"login"`
+			} `json:"sender"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, err
+		}
+		return &Event{
+			Repo:   payload.Repository.FullName,
+			Ref:    payload.PullRequest.Head.Ref,
+			Commit: payload.PullRequest.Head.SHA,
+			Pusher: payload.Sender.Login,
+		}, nil
+
+	default:
+		// Events we don't act on, e.g. ping.
+		return nil, nil
+	}
+}
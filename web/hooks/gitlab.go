@@ -0,0 +1,70 @@
+package hooks
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func init() {
+	Register("gitlab", gitlabParser{})
+}
+
+// gitlabParser parses GitLab webhook deliveries, authenticated with a static
+// X-Gitlab-Token header rather than a request signature.
+type gitlabParser struct{}
+
+func (gitlabParser) Verify(r *http.Request, body []byte, secret string) error {
+	return verifyToken(r.Header.Get("X-Gitlab-Token"), secret)
+}
+
+func (gitlabParser) Parse(r *http.Request, body []byte) (*Event, error) {
+	switch r.Header.Get("X-Gitlab-Event") {
+	case "Push Hook":
+		var payload struct {
+			Ref        string `json:"ref"`
+			After      string `json:"after"`
+			UserName   string `json:"user_name"`
+			Repository struct {
+				PathWithNamespace string `json:"path_with_namespace"`
+			} `json:"repository"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, err
+		}
+		return &Event{
+			Repo:   payload.Repository.PathWithNamespace,
+			Ref:    payload.Ref,
+			Commit: payload.After,
+			Pusher: payload.UserName,
+		}, nil
+
+	case "Merge Request Hook":
+		var payload struct {
+			User struct {
+				Username string `json:"username"`
+			} `json:"user"`
+			ObjectAttributes struct {
+				SourceBranch string `json:"source_branch"`
+				LastCommit   struct {
+					ID string `json:"id"`
+				} `json:"last_commit"`
+				Source struct {
+					PathWithNamespace string `json:"path_with_namespace"`
+				} `json:"source"`
+			} `json:"object_attributes"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, err
+		}
+		return &Event{
+			Repo:   payload.ObjectAttributes.Source.PathWithNamespace,
+			Ref:    payload.ObjectAttributes.SourceBranch,
+			Commit: payload.ObjectAttributes.LastCommit.ID,
+			Pusher: payload.User.Username,
+		}, nil
+
+	default:
+		// Events we don't act on, e.g. a system hook ping.
+		return nil, nil
+	}
+}
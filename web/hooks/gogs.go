@@ -0,0 +1,55 @@
+package hooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+func init() {
+	parser := gogsParser{}
+	Register("gogs", parser)
+	// Gitea speaks the same webhook format as Gogs, its ancestor.
+	Register("gitea", parser)
+}
+
+// gogsParser parses Gogs and Gitea webhook deliveries, signed with
+// X-Gogs-Signature.
+type gogsParser struct{}
+
+func (gogsParser) Verify(r *http.Request, body []byte, secret string) error {
+	sig := r.Header.Get("X-Gogs-Signature")
+	if sig == "" {
+		return errors.New("missing X-Gogs-Signature header")
+	}
+	return verifyHMACSHA256("", sig, body, secret)
+}
+
+func (gogsParser) Parse(r *http.Request, body []byte) (*Event, error) {
+	switch r.Header.Get("X-Gogs-Event") {
+	case "push":
+		var payload struct {
+			Ref        string `json:"ref"`
+			After      string `json:"after"`
+			Repository struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+			Pusher struct {
+				Username string `json:"username"`
+			} `json:"pusher"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, err
+		}
+		return &Event{
+			Repo:   payload.Repository.FullName,
+			Ref:    payload.Ref,
+			Commit: payload.After,
+			Pusher: payload.Pusher.Username,
+		}, nil
+
+	default:
+		// Events we don't act on, e.g. ping.
+		return nil, nil
+	}
+}
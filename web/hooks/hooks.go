@@ -0,0 +1,86 @@
+// Package hooks receives and dispatches webhook notifications from SCM
+// providers so that a push or pull-request can trigger assignment grading.
+package hooks
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/labstack/echo"
+)
+
+// Event is a provider-agnostic representation of a push or pull-request
+// notification received from an SCM webhook.
+type Event struct {
+	Provider string
+	Repo     string
+	Ref      string
+	Commit   string
+	Pusher   string
+}
+
+// Parser turns a provider's raw webhook request into an Event.
+type Parser interface {
+	// Verify checks the request's signature or token against secret.
+	Verify(r *http.Request, body []byte, secret string) error
+	// Parse extracts an Event from the verified request body. It returns a
+	// nil Event, nil error for event types the caller does not act on,
+	// e.g. a ping.
+	Parse(r *http.Request, body []byte) (*Event, error)
+}
+
+var parsers = make(map[string]Parser)
+
+// Register makes a provider's webhook Parser available under name. It is
+// meant to be called from the init function of the file implementing the
+// parser, and panics if called twice for the same name.
+func Register(name string, parser Parser) {
+	if parser == nil {
+		panic("hooks: Register parser is nil for provider " + name)
+	}
+	if _, dup := parsers[name]; dup {
+		panic("hooks: Register called twice for provider " + name)
+	}
+	parsers[name] = parser
+}
+
+// Handler returns an echo.HandlerFunc that verifies and parses an incoming
+// webhook request for the :provider route param and enqueues the resulting
+// Event on q.
+func Handler(q *Queue, secret string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		provider := c.Param("provider")
+		parser, ok := parsers[provider]
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotFound, "unknown hook provider: "+provider)
+		}
+
+		body, err := ioutil.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "could not read request body")
+		}
+
+		if err := parser.Verify(c.Request(), body, secret); err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid hook signature")
+		}
+
+		event, err := parser.Parse(c.Request(), body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if event == nil {
+			return c.NoContent(http.StatusOK)
+		}
+		event.Provider = provider
+
+		if !q.Enqueue(event) {
+			return echo.NewHTTPError(http.StatusServiceUnavailable, "hook queue is full")
+		}
+		return c.NoContent(http.StatusAccepted)
+	}
+}
+
+// RegisterRoutes mounts the webhook endpoint at /hooks/:provider on api.
+func RegisterRoutes(api *echo.Group, q *Queue, secret string) {
+	api.POST("/hooks/:provider", Handler(q, secret))
+}
@@ -0,0 +1,93 @@
+package hooks
+
+import "context"
+
+// HandleFunc processes a single Event dispatched from the queue, e.g. by
+// looking up the course for event.Repo and triggering assignment grading.
+type HandleFunc func(context.Context, *Event)
+
+// Queue buffers parsed Events and dispatches them to a fixed pool of workers
+// running handle, decoupling webhook delivery from event processing.
+type Queue struct {
+	events  chan *Event
+	closing chan struct{} // closed by Shutdown to stop accepting new events
+	handle  HandleFunc
+	workers int
+	done    chan struct{}
+}
+
+// NewQueue starts a Queue with the given buffer size and number of workers,
+// each running handle for every Event it receives.
+func NewQueue(bufSize, workers int, handle HandleFunc) *Queue {
+	q := &Queue{
+		events:  make(chan *Event, bufSize),
+		closing: make(chan struct{}),
+		handle:  handle,
+		workers: workers,
+		done:    make(chan struct{}, workers),
+	}
+	for i := 0; i < workers; i++ {
+		go q.worker()
+	}
+	return q
+}
+
+func (q *Queue) worker() {
+	for {
+		select {
+		case event := <-q.events:
+			q.handle(context.Background(), event)
+		case <-q.closing:
+			q.drain()
+			q.done <- struct{}{}
+			return
+		}
+	}
+}
+
+// drain runs handle for every event still buffered in q.events, called once
+// a worker has seen q.closing so that Shutdown's wait for q.done only
+// returns once every buffered event has actually been processed.
+func (q *Queue) drain() {
+	for {
+		select {
+		case event := <-q.events:
+			q.handle(context.Background(), event)
+		default:
+			return
+		}
+	}
+}
+
+// Enqueue adds event to the queue for processing. It reports false without
+// blocking if the queue is full or Shutdown has been called, so that a slow
+// consumer cannot stall webhook delivery and a webhook handler still
+// in-flight when Shutdown runs cannot send on a channel Shutdown has closed.
+func (q *Queue) Enqueue(event *Event) bool {
+	select {
+	case <-q.closing:
+		return false
+	default:
+	}
+	select {
+	case q.events <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+// Shutdown stops the queue from accepting new events and waits for every
+// worker to drain its in-flight and buffered events, or returns ctx's error
+// if it is cancelled first.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	close(q.closing)
+	for i := 0; i < q.workers; i++ {
+		select {
+		case <-q.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// verifyHMACSHA256 checks that signature, after stripping prefix, is the
+// hex-encoded HMAC-SHA256 of body keyed by secret.
+func verifyHMACSHA256(prefix, signature string, body []byte, secret string) error {
+	signature = strings.TrimPrefix(signature, prefix)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return errors.New("hmac signature mismatch")
+	}
+	return nil
+}
+
+// verifyToken checks that token equals secret using a constant-time
+// comparison.
+func verifyToken(token, secret string) error {
+	if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return errors.New("token mismatch")
+	}
+	return nil
+}